@@ -0,0 +1,18 @@
+// Command apiv1 serves the API behind apex/gateway v1
+// (APIGatewayProxyRequest / REST API) events.
+package main
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/kaihendry/aws-lambda-rest-iam-api/server"
+	"github.com/kaihendry/aws-lambda-rest-iam-api/server/gatewayv1"
+)
+
+func main() {
+	if err := server.New().ListenAndServe(gatewayv1.Adapter{}); err != nil {
+		slog.Error("Server error", "error", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,18 @@
+// Command apiv2 serves the API behind apex/gateway v2
+// (APIGatewayV2HTTPRequest / HTTP API) events.
+package main
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/kaihendry/aws-lambda-rest-iam-api/server"
+	"github.com/kaihendry/aws-lambda-rest-iam-api/server/gatewayv2"
+)
+
+func main() {
+	if err := server.New().ListenAndServe(gatewayv2.Adapter{}); err != nil {
+		slog.Error("Server error", "error", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,108 @@
+package iamauth
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// identityCache is a fixed-size, TTL-bounded LRU cache of verified
+// CallerInfo lookups, keyed by the caller's access-key ID and a hash of
+// their security token. It exists so a long-lived Lambda execution
+// environment doesn't make an STS call on every invocation from the same
+// caller.
+type identityCache struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	maxItems int
+	order    *list.List // front = most recently used
+	items    map[string]*list.Element
+}
+
+type cacheEntry struct {
+	key      string
+	caller   CallerInfo
+	expireAt time.Time
+}
+
+func newIdentityCache(maxItems int, ttl time.Duration) *identityCache {
+	return &identityCache{
+		ttl:      ttl,
+		maxItems: maxItems,
+		order:    list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+func (c *identityCache) get(key string) (CallerInfo, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return CallerInfo{}, false
+	}
+	entry := el.Value.(*cacheEntry)
+	if time.Now().After(entry.expireAt) {
+		c.order.Remove(el)
+		delete(c.items, key)
+		return CallerInfo{}, false
+	}
+	c.order.MoveToFront(el)
+	return entry.caller, true
+}
+
+func (c *identityCache) put(key string, caller CallerInfo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		el.Value.(*cacheEntry).caller = caller
+		el.Value.(*cacheEntry).expireAt = time.Now().Add(c.ttl)
+		c.order.MoveToFront(el)
+		return
+	}
+
+	el := c.order.PushFront(&cacheEntry{key: key, caller: caller, expireAt: time.Now().Add(c.ttl)})
+	c.items[key] = el
+
+	if c.order.Len() > c.maxItems {
+		oldest := c.order.Back()
+		if oldest != nil {
+			c.order.Remove(oldest)
+			delete(c.items, oldest.Value.(*cacheEntry).key)
+		}
+	}
+}
+
+// identityCacheKey derives a cache key from the caller's access-key ID
+// (read from the pre-signed Authorization header's Credential field) and
+// a SHA-256 hash of their security token, so the raw token is never held
+// in memory as a map key.
+func identityCacheKey(signedHeaders http.Header) string {
+	accessKeyID := credentialAccessKeyID(signedHeaders.Get("Authorization"))
+	token := signedHeaders.Get("X-Amz-Security-Token")
+	sum := sha256.Sum256([]byte(token))
+	return accessKeyID + ":" + hex.EncodeToString(sum[:])
+}
+
+// credentialAccessKeyID extracts the access key ID from a SigV4
+// Authorization header of the form:
+//
+//	AWS4-HMAC-SHA256 Credential=AKIA.../20240101/us-east-1/sts/aws4_request, ...
+func credentialAccessKeyID(authHeader string) string {
+	const marker = "Credential="
+	idx := strings.Index(authHeader, marker)
+	if idx == -1 {
+		return ""
+	}
+	rest := authHeader[idx+len(marker):]
+	if end := strings.Index(rest, "/"); end != -1 {
+		return rest[:end]
+	}
+	return rest
+}
@@ -0,0 +1,323 @@
+// Package iamauth verifies caller identity using the same pattern as
+// Consul's aws-iam auth method: the client signs an STS
+// sts:GetCallerIdentity request with SigV4 and hands the server the
+// pre-signed request (method, URL, headers, body) inside a handful of
+// X-Iam-Getcalleridentity-* headers instead of a bearer token. The server
+// replays that exact request against STS and trusts whatever ARN comes
+// back, since only the holder of valid AWS credentials could have
+// produced a signature STS accepts.
+package iamauth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/kaihendry/aws-lambda-rest-iam-api/tracecontext"
+)
+
+const (
+	HeaderMethod   = "X-Iam-Getcalleridentity-Method"
+	HeaderURL      = "X-Iam-Getcalleridentity-Url"
+	HeaderHeaders  = "X-Iam-Getcalleridentity-Headers"
+	HeaderBody     = "X-Iam-Getcalleridentity-Body"
+	HeaderServerID = "X-Iam-Server-Id"
+)
+
+var (
+	// ErrMissingHeaders is returned when one of the required
+	// X-Iam-Getcalleridentity-* headers is absent.
+	ErrMissingHeaders = errors.New("iamauth: missing GetCallerIdentity headers")
+	// ErrServerIDMismatch is returned when the caller's server-ID header
+	// does not match this Authenticator's configured audience, which
+	// stops a signed request from being replayed against a different
+	// service.
+	ErrServerIDMismatch = errors.New("iamauth: server-ID header missing or does not match")
+	// ErrNotSTSHost is returned when the pre-signed request does not
+	// target an STS endpoint.
+	ErrNotSTSHost = errors.New("iamauth: GetCallerIdentity URL does not target an STS endpoint")
+	// ErrSTSRequestFailed is returned when STS rejects the forwarded
+	// request (expired signature, revoked credentials, etc).
+	ErrSTSRequestFailed = errors.New("iamauth: STS rejected the forwarded request")
+)
+
+// CallerInfo is the verified identity of the AWS principal that signed a
+// GetCallerIdentity request.
+type CallerInfo struct {
+	UserARN     string `json:"user_arn,omitempty"`
+	AccountID   string `json:"account_id,omitempty"`
+	PrincipalID string `json:"principal_id,omitempty"`
+	RoleName    string `json:"role_name,omitempty"`
+	SessionName string `json:"session_name,omitempty"`
+}
+
+// Config controls how an Authenticator verifies and authorizes callers.
+// When both allow-lists are set, a caller matching either one is
+// authorized.
+type Config struct {
+	// ServerID must match the X-Iam-Server-Id header on every request.
+	// Binding verification to this Lambda's expected audience prevents a
+	// presigned request captured by one service from being replayed
+	// against another.
+	ServerID string
+	// AllowedRoleARNs, if non-empty, restricts authorization to callers
+	// assuming one of these IAM role ARNs.
+	AllowedRoleARNs []string
+	// AllowedAccountIDs, if non-empty, restricts authorization to
+	// callers belonging to one of these AWS account IDs.
+	AllowedAccountIDs []string
+}
+
+// ConfigFromEnv builds a Config from IAM_SERVER_ID, IAM_ALLOWED_ROLE_ARNS
+// and IAM_ALLOWED_ACCOUNT_IDS (comma-separated).
+func ConfigFromEnv() Config {
+	return Config{
+		ServerID:          os.Getenv("IAM_SERVER_ID"),
+		AllowedRoleARNs:   splitAndTrim(os.Getenv("IAM_ALLOWED_ROLE_ARNS")),
+		AllowedAccountIDs: splitAndTrim(os.Getenv("IAM_ALLOWED_ACCOUNT_IDS")),
+	}
+}
+
+func splitAndTrim(s string) []string {
+	if s == "" {
+		return nil
+	}
+	parts := strings.Split(s, ",")
+	out := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+const (
+	identityCacheMaxItems = 1000
+	identityCacheTTL      = 5 * time.Minute
+)
+
+// Authenticator verifies callers against STS.
+type Authenticator struct {
+	cfg        Config
+	httpClient httpDoer
+	cache      *identityCache
+}
+
+// NewAuthenticator returns an Authenticator that verifies callers
+// according to cfg. It loads the default AWS credential chain (IMDSv2
+// aware) up front; if that fails - e.g. no IMDS endpoint and no other
+// credential source reachable - it falls back to a plain HTTP client so
+// that a container without its own AWS credentials can still forward
+// caller-signed requests to STS.
+func NewAuthenticator(cfg Config) *Authenticator {
+	httpClient, err := newSTSHTTPClient(context.Background())
+	if err != nil {
+		httpClient = &http.Client{Timeout: 5 * time.Second}
+	}
+	return &Authenticator{
+		cfg:        cfg,
+		httpClient: httpClient,
+		cache:      newIdentityCache(identityCacheMaxItems, identityCacheTTL),
+	}
+}
+
+// Authenticate reconstructs the caller's pre-signed sts:GetCallerIdentity
+// request from r's headers, verifies the server-ID binding, replays the
+// request against STS (or serves a cached identity for the same
+// access-key/security-token pair), and returns the verified caller
+// identity.
+func (a *Authenticator) Authenticate(ctx context.Context, r *http.Request) (*CallerInfo, error) {
+	if serverID := r.Header.Get(HeaderServerID); serverID == "" || serverID != a.cfg.ServerID {
+		return nil, ErrServerIDMismatch
+	}
+
+	stsReq, err := a.buildSTSRequest(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheKey := identityCacheKey(stsReq.Header)
+	if cached, ok := a.cache.get(cacheKey); ok {
+		caller := cached
+		return &caller, nil
+	}
+
+	resp, err := a.httpClient.Do(stsReq)
+	if err != nil {
+		return nil, fmt.Errorf("iamauth: calling STS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("iamauth: reading STS response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: status %d: %s", ErrSTSRequestFailed, resp.StatusCode, string(body))
+	}
+
+	arn, account, userID, err := parseCallerIdentityResponse(body)
+	if err != nil {
+		return nil, err
+	}
+
+	roleName, sessionName := splitAssumedRoleARN(arn)
+	caller := CallerInfo{
+		UserARN:     arn,
+		AccountID:   account,
+		PrincipalID: userID,
+		RoleName:    roleName,
+		SessionName: sessionName,
+	}
+	a.cache.put(cacheKey, caller)
+	return &caller, nil
+}
+
+// buildSTSRequest reconstructs the caller's signed request from the
+// X-Iam-Getcalleridentity-* headers on r.
+func (a *Authenticator) buildSTSRequest(ctx context.Context, r *http.Request) (*http.Request, error) {
+	method := r.Header.Get(HeaderMethod)
+	encodedURL := r.Header.Get(HeaderURL)
+	encodedHeaders := r.Header.Get(HeaderHeaders)
+	encodedBody := r.Header.Get(HeaderBody)
+	if method == "" || encodedURL == "" || encodedHeaders == "" {
+		return nil, ErrMissingHeaders
+	}
+
+	rawURL, err := base64.StdEncoding.DecodeString(encodedURL)
+	if err != nil {
+		return nil, fmt.Errorf("iamauth: decoding %s: %w", HeaderURL, err)
+	}
+	if !isSTSHost(string(rawURL)) {
+		return nil, ErrNotSTSHost
+	}
+
+	var body []byte
+	if encodedBody != "" {
+		body, err = base64.StdEncoding.DecodeString(encodedBody)
+		if err != nil {
+			return nil, fmt.Errorf("iamauth: decoding %s: %w", HeaderBody, err)
+		}
+	}
+
+	stsReq, err := http.NewRequestWithContext(ctx, method, string(rawURL), strings.NewReader(string(body)))
+	if err != nil {
+		return nil, fmt.Errorf("iamauth: building STS request: %w", err)
+	}
+
+	rawHeaders, err := base64.StdEncoding.DecodeString(encodedHeaders)
+	if err != nil {
+		return nil, fmt.Errorf("iamauth: decoding %s: %w", HeaderHeaders, err)
+	}
+	var signedHeaders map[string][]string
+	if err := json.Unmarshal(rawHeaders, &signedHeaders); err != nil {
+		return nil, fmt.Errorf("iamauth: parsing %s: %w", HeaderHeaders, err)
+	}
+	for name, values := range signedHeaders {
+		for _, v := range values {
+			stsReq.Header.Add(name, v)
+		}
+	}
+
+	// Forward the caller's trace context, if any, so the outbound STS
+	// call correlates with the request that triggered it - both the W3C
+	// traceparent and, since STS itself is an AWS call, the equivalent
+	// X-Ray header.
+	if tc, ok := tracecontext.FromContext(ctx); ok {
+		if tc.TraceParent != "" {
+			stsReq.Header.Set("traceparent", tc.TraceParent)
+		}
+		if xrayHeader := tc.XRayHeader(); xrayHeader != "" {
+			stsReq.Header.Set("X-Amzn-Trace-Id", xrayHeader)
+		}
+	}
+
+	return stsReq, nil
+}
+
+var stsHostPattern = regexp.MustCompile(`^sts(\.[a-z0-9-]+)?\.amazonaws\.com$`)
+
+func isSTSHost(rawURL string) bool {
+	host := rawURL
+	if idx := strings.Index(host, "://"); idx != -1 {
+		host = host[idx+3:]
+	}
+	if idx := strings.IndexAny(host, "/?"); idx != -1 {
+		host = host[:idx]
+	}
+	if idx := strings.Index(host, ":"); idx != -1 {
+		host = host[:idx]
+	}
+	return stsHostPattern.MatchString(host)
+}
+
+type getCallerIdentityResponse struct {
+	XMLName xml.Name `xml:"GetCallerIdentityResponse"`
+	Result  struct {
+		Arn     string `xml:"Arn"`
+		UserId  string `xml:"UserId"`
+		Account string `xml:"Account"`
+	} `xml:"GetCallerIdentityResult"`
+}
+
+func parseCallerIdentityResponse(body []byte) (arn, account, userID string, err error) {
+	var parsed getCallerIdentityResponse
+	if err := xml.Unmarshal(body, &parsed); err != nil {
+		return "", "", "", fmt.Errorf("iamauth: parsing GetCallerIdentity response: %w", err)
+	}
+	if parsed.Result.Arn == "" {
+		return "", "", "", fmt.Errorf("%w: empty Arn in response", ErrSTSRequestFailed)
+	}
+	return parsed.Result.Arn, parsed.Result.Account, parsed.Result.UserId, nil
+}
+
+// assumedRoleARN matches arn:aws:sts::<account>:assumed-role/<role>/<session>
+// and deterministically captures the role and session names.
+var assumedRoleARN = regexp.MustCompile(`^arn:aws:sts::\d+:assumed-role/([^/]+)/(.+)$`)
+
+// splitAssumedRoleARN splits an assumed-role ARN into its role and
+// session names. It returns empty strings for any other ARN shape.
+func splitAssumedRoleARN(arn string) (roleName, sessionName string) {
+	matches := assumedRoleARN.FindStringSubmatch(arn)
+	if matches == nil {
+		return "", ""
+	}
+	return matches[1], matches[2]
+}
+
+// Authorized reports whether caller is permitted to access an
+// authorization-gated route, per the Authenticator's allow-lists. An
+// Authenticator configured with no allow-lists permits any verified
+// caller.
+func (a *Authenticator) Authorized(caller *CallerInfo) bool {
+	if len(a.cfg.AllowedRoleARNs) == 0 && len(a.cfg.AllowedAccountIDs) == 0 {
+		return true
+	}
+
+	if caller.RoleName != "" && caller.AccountID != "" {
+		roleARN := fmt.Sprintf("arn:aws:iam::%s:role/%s", caller.AccountID, caller.RoleName)
+		for _, allowed := range a.cfg.AllowedRoleARNs {
+			if allowed == roleARN || allowed == caller.UserARN {
+				return true
+			}
+		}
+	}
+
+	for _, allowed := range a.cfg.AllowedAccountIDs {
+		if allowed == caller.AccountID {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,157 @@
+package iamauth
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIsSTSHost(t *testing.T) {
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"https://sts.amazonaws.com/", true},
+		{"https://sts.us-east-1.amazonaws.com/", true},
+		{"https://sts.ap-southeast-1.amazonaws.com:443/", true},
+		{"http://sts.amazonaws.com/?Action=GetCallerIdentity", true},
+		{"https://evil.example.com/", false},
+		// A host that merely contains the STS domain as a suffix or
+		// prefix must not be accepted - that would let a forged
+		// pre-signed request target an attacker-controlled server.
+		{"https://sts.amazonaws.com.evil.example.com/", false},
+		{"https://evil.example.com/sts.amazonaws.com", false},
+		{"https://notsts.amazonaws.com/", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			if got := isSTSHost(tt.url); got != tt.want {
+				t.Errorf("isSTSHost(%q) = %v, want %v", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitAssumedRoleARN(t *testing.T) {
+	tests := []struct {
+		arn             string
+		wantRole        string
+		wantSessionName string
+	}{
+		{
+			arn:             "arn:aws:sts::123456789012:assumed-role/my-role/my-session",
+			wantRole:        "my-role",
+			wantSessionName: "my-session",
+		},
+		{
+			// Session names can themselves contain slashes.
+			arn:             "arn:aws:sts::123456789012:assumed-role/my-role/team/my-session",
+			wantRole:        "my-role",
+			wantSessionName: "team/my-session",
+		},
+		{
+			arn:             "arn:aws:iam::123456789012:user/alice",
+			wantRole:        "",
+			wantSessionName: "",
+		},
+		{
+			arn:             "not-an-arn",
+			wantRole:        "",
+			wantSessionName: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arn, func(t *testing.T) {
+			role, session := splitAssumedRoleARN(tt.arn)
+			if role != tt.wantRole || session != tt.wantSessionName {
+				t.Errorf("splitAssumedRoleARN(%q) = (%q, %q), want (%q, %q)",
+					tt.arn, role, session, tt.wantRole, tt.wantSessionName)
+			}
+		})
+	}
+}
+
+func TestAuthorized(t *testing.T) {
+	tests := []struct {
+		name   string
+		cfg    Config
+		caller *CallerInfo
+		want   bool
+	}{
+		{
+			name:   "no allow-list permits any verified caller",
+			cfg:    Config{},
+			caller: &CallerInfo{UserARN: "arn:aws:sts::111111111111:assumed-role/any-role/session"},
+			want:   true,
+		},
+		{
+			name: "role ARN on the allow-list is permitted",
+			cfg:  Config{AllowedRoleARNs: []string{"arn:aws:iam::111111111111:role/deploy"}},
+			caller: &CallerInfo{
+				UserARN:   "arn:aws:sts::111111111111:assumed-role/deploy/session",
+				AccountID: "111111111111",
+				RoleName:  "deploy",
+			},
+			want: true,
+		},
+		{
+			name: "role not on the allow-list is forbidden",
+			cfg:  Config{AllowedRoleARNs: []string{"arn:aws:iam::111111111111:role/deploy"}},
+			caller: &CallerInfo{
+				UserARN:   "arn:aws:sts::111111111111:assumed-role/other-role/session",
+				AccountID: "111111111111",
+				RoleName:  "other-role",
+			},
+			want: false,
+		},
+		{
+			name:   "account on the allow-list is permitted",
+			cfg:    Config{AllowedAccountIDs: []string{"222222222222"}},
+			caller: &CallerInfo{AccountID: "222222222222"},
+			want:   true,
+		},
+		{
+			name:   "account not on the allow-list is forbidden",
+			cfg:    Config{AllowedAccountIDs: []string{"222222222222"}},
+			caller: &CallerInfo{AccountID: "333333333333"},
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewAuthenticator(tt.cfg)
+			if got := a.Authorized(tt.caller); got != tt.want {
+				t.Errorf("Authorized() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthenticateServerIDMismatch(t *testing.T) {
+	a := NewAuthenticator(Config{ServerID: "expected-audience"})
+
+	tests := []struct {
+		name            string
+		serverIDHeader  string
+		wantMissingOrOK bool
+	}{
+		{name: "missing server-ID header", serverIDHeader: ""},
+		{name: "wrong server-ID header", serverIDHeader: "some-other-audience"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/data", nil)
+			if tt.serverIDHeader != "" {
+				req.Header.Set(HeaderServerID, tt.serverIDHeader)
+			}
+
+			_, err := a.Authenticate(req.Context(), req)
+			if err != ErrServerIDMismatch {
+				t.Errorf("Authenticate() error = %v, want %v", err, ErrServerIDMismatch)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package iamauth
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/feature/ec2/imds"
+)
+
+// maxSTSAttempts bounds how many times a forward to STS is attempted in
+// total (the first try plus up to three retries while throttled) before
+// the caller sees the throttled response.
+const maxSTSAttempts = 4
+
+// httpDoer is the subset of *http.Client used to forward requests to
+// STS, so it can be backed by either a plain http.Client or the AWS
+// SDK's own HTTPClient.
+type httpDoer interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// newSTSHTTPClient returns an httpDoer for forwarding pre-signed
+// GetCallerIdentity requests to STS. It loads the default AWS credential
+// chain with the EC2 IMDS client explicitly enabled (this option only
+// sets whether the IMDS client is enabled; the SDK's IMDSv1 fallback is
+// left at its default and is not turned off here) and forwards through the
+// resulting aws.Config's HTTPClient, so this Lambda resolves credentials
+// safely - and shares the same transport, proxy and TLS settings - if it
+// ever runs on EC2 or ECS instead. The caller's request is itself already
+// signed, so no credentials from this chain are used to sign it; wrapping
+// the transport only adds STS-specific exponential-backoff retry on top.
+func newSTSHTTPClient(ctx context.Context) (httpDoer, error) {
+	cfg, err := config.LoadDefaultConfig(ctx,
+		config.WithEC2IMDSClientEnableState(imds.ClientEnabled),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("iamauth: loading AWS config: %w", err)
+	}
+
+	return &retryingClient{base: cfg.HTTPClient}, nil
+}
+
+// retryingClient retries throttled STS responses with jittered
+// exponential backoff.
+type retryingClient struct {
+	base httpDoer
+}
+
+func (c *retryingClient) Do(req *http.Request) (*http.Response, error) {
+	var bodyBytes []byte
+	if req.Body != nil {
+		var err error
+		bodyBytes, err = io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+	}
+
+	var resp *http.Response
+	var err error
+	for attempt := 0; attempt < maxSTSAttempts; attempt++ {
+		if bodyBytes != nil {
+			req.Body = io.NopCloser(strings.NewReader(string(bodyBytes)))
+		}
+
+		resp, err = c.base.Do(req)
+		if err != nil || !isThrottled(resp) {
+			return resp, err
+		}
+		if attempt == maxSTSAttempts-1 {
+			return resp, err
+		}
+
+		resp.Body.Close()
+		time.Sleep(backoff(attempt))
+	}
+	return resp, err
+}
+
+func isThrottled(resp *http.Response) bool {
+	return resp != nil && resp.StatusCode == http.StatusTooManyRequests
+}
+
+// backoff returns a jittered exponential delay: 100ms, 200ms, 400ms, ...
+// up to a 2s ceiling.
+func backoff(attempt int) time.Duration {
+	base := 100 * time.Millisecond
+	delay := base << attempt
+	const ceiling = 2 * time.Second
+	if delay > ceiling {
+		delay = ceiling
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
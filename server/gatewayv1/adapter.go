@@ -0,0 +1,17 @@
+// Package gatewayv1 adapts apex/gateway (the APIGatewayProxyRequest /
+// REST API payload version) to the server.Router interface.
+package gatewayv1
+
+import (
+	"net/http"
+
+	"github.com/apex/gateway"
+)
+
+// Adapter implements server.Router using apex/gateway v1.
+type Adapter struct{}
+
+// ListenAndServe implements server.Router.
+func (Adapter) ListenAndServe(addr string, h http.Handler) error {
+	return gateway.ListenAndServe(addr, h)
+}
@@ -0,0 +1,17 @@
+// Package gatewayv2 adapts apex/gateway/v2 (the APIGatewayV2HTTPRequest
+// / HTTP API payload version) to the server.Router interface.
+package gatewayv2
+
+import (
+	"net/http"
+
+	"github.com/apex/gateway/v2"
+)
+
+// Adapter implements server.Router using apex/gateway v2.
+type Adapter struct{}
+
+// ListenAndServe implements server.Router.
+func (Adapter) ListenAndServe(addr string, h http.Handler) error {
+	return gateway.ListenAndServe(addr, h)
+}
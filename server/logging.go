@@ -0,0 +1,105 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/kaihendry/aws-lambda-rest-iam-api/tracecontext"
+)
+
+type callerContextKey struct{}
+
+// withCaller returns a context carrying caller, so handlers can read the
+// identity accessLogMiddleware already authenticated instead of
+// authenticating again.
+func withCaller(ctx context.Context, caller *CallerInfo) context.Context {
+	return context.WithValue(ctx, callerContextKey{}, caller)
+}
+
+// callerFromContext returns the caller stashed by withCaller. It never
+// returns nil: a request without iamauth headers carries an
+// "Unauthenticated Request" CallerInfo, and a request whose
+// authentication failed, or whose context holds no caller, gets an
+// empty CallerInfo.
+func callerFromContext(ctx context.Context) *CallerInfo {
+	if caller, ok := ctx.Value(callerContextKey{}).(*CallerInfo); ok && caller != nil {
+		return caller
+	}
+	return &CallerInfo{}
+}
+
+// statusRecorder wraps http.ResponseWriter to capture the status code and
+// byte count written, so the access-log middleware can report them after
+// the handler returns.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (rec *statusRecorder) WriteHeader(status int) {
+	rec.status = status
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *statusRecorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	n, err := rec.ResponseWriter.Write(b)
+	rec.bytes += n
+	return n, err
+}
+
+// accessLogMiddleware authenticates the caller once, stashes it and the
+// parsed trace context on the request's context for handlers and
+// downstream AWS SDK calls (e.g. the STS lookups in iamauth) to reuse,
+// and emits a single structured JSON log line per request with enough
+// fields for CloudWatch Logs Insights to query directly.
+func (s *Server) accessLogMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		tc := tracecontext.FromRequest(r)
+		ctx := tracecontext.With(r.Context(), tc)
+
+		caller, err := s.authenticateCaller(r.WithContext(ctx))
+		if err != nil {
+			slog.Warn("caller authentication failed", "error", err)
+			caller = &CallerInfo{}
+		}
+		ctx = withCaller(ctx, caller)
+		r = r.WithContext(ctx)
+
+		rec := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+
+		slog.Info("request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", rec.status,
+			"latency_ms", time.Since(start).Milliseconds(),
+			"bytes", rec.bytes,
+			"caller_arn", caller.UserARN,
+			"request_id", r.Header.Get("X-Request-Id"),
+			"trace_root", tc.Root,
+			"trace_parent", tc.Parent,
+			"trace_sampled", tc.Sampled,
+			"traceparent", tc.TraceParent,
+		)
+	})
+}
+
+// ConfigureLogging switches the default slog handler to JSON when
+// running under Lambda, so CloudWatch Logs Insights can query the
+// structured fields from accessLogMiddleware directly.
+func ConfigureLogging(underLambda bool) {
+	if underLambda {
+		slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+		return
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stdout, nil)))
+}
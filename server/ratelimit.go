@@ -0,0 +1,204 @@
+package server
+
+import (
+	"container/list"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// routeLimit names the environment variable that configures the rate
+// limit for one (method, path) route.
+type routeLimit struct {
+	method string
+	path   string
+	envVar string
+}
+
+// rateLimitedRoutes lists every route that can be rate-limited and the
+// environment variable that configures it, e.g. RATE_LIMIT_DATA_POST=10/s
+// limits POST /data to 10 requests/second per caller.
+var rateLimitedRoutes = []routeLimit{
+	{http.MethodGet, "/", "RATE_LIMIT_ROOT_GET"},
+	{http.MethodGet, "/health", "RATE_LIMIT_HEALTH_GET"},
+	{http.MethodGet, "/data", "RATE_LIMIT_DATA_GET"},
+	{http.MethodPost, "/data", "RATE_LIMIT_DATA_POST"},
+}
+
+// maxLimitersPerRoute bounds how many distinct callers' token buckets a
+// single route keeps in memory at once, so a long-lived Lambda container
+// fielding many distinct IAM principals doesn't grow unbounded.
+const maxLimitersPerRoute = 10000
+
+// numLimiterShards splits each route's limiter map across this many
+// independently-locked shards, so concurrent requests from different
+// callers don't serialize on a single mutex.
+const numLimiterShards = 16
+
+type rateLimitSpec struct {
+	limit rate.Limit
+	burst int
+}
+
+// parseRateLimitSpec parses a "N/s" rate-limit spec, e.g. "10/s", into a
+// token-bucket rate and a burst equal to one second's worth of requests.
+func parseRateLimitSpec(s string) (rateLimitSpec, error) {
+	n, ok := strings.CutSuffix(s, "/s")
+	if !ok {
+		return rateLimitSpec{}, fmt.Errorf("rate limit %q must be of the form N/s", s)
+	}
+	requestsPerSecond, err := strconv.Atoi(n)
+	if err != nil || requestsPerSecond <= 0 {
+		return rateLimitSpec{}, fmt.Errorf("rate limit %q must be a positive integer number of requests per second", s)
+	}
+	return rateLimitSpec{limit: rate.Limit(requestsPerSecond), burst: requestsPerSecond}, nil
+}
+
+// routeLimitsFromEnv reads rateLimitedRoutes' environment variables into
+// a lookup keyed by "METHOD PATH". Routes with no (or an invalid)
+// environment variable are left unlimited.
+func routeLimitsFromEnv() map[string]rateLimitSpec {
+	specs := make(map[string]rateLimitSpec)
+	for _, route := range rateLimitedRoutes {
+		raw := os.Getenv(route.envVar)
+		if raw == "" {
+			continue
+		}
+		spec, err := parseRateLimitSpec(raw)
+		if err != nil {
+			slog.Error("ignoring invalid rate limit", "env", route.envVar, "value", raw, "error", err)
+			continue
+		}
+		specs[route.method+" "+route.path] = spec
+	}
+	return specs
+}
+
+// limiterShard is one independently-locked, LRU-bounded shard of a
+// limiterStore.
+type limiterShard struct {
+	mu       sync.Mutex
+	maxItems int
+	order    *list.List
+	items    map[string]*list.Element
+}
+
+type limiterEntry struct {
+	key     string
+	limiter *rate.Limiter
+}
+
+func (sh *limiterShard) get(key string, spec rateLimitSpec) *rate.Limiter {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	if el, ok := sh.items[key]; ok {
+		sh.order.MoveToFront(el)
+		return el.Value.(*limiterEntry).limiter
+	}
+
+	limiter := rate.NewLimiter(spec.limit, spec.burst)
+	el := sh.order.PushFront(&limiterEntry{key: key, limiter: limiter})
+	sh.items[key] = el
+
+	if sh.order.Len() > sh.maxItems {
+		oldest := sh.order.Back()
+		if oldest != nil {
+			sh.order.Remove(oldest)
+			delete(sh.items, oldest.Value.(*limiterEntry).key)
+		}
+	}
+
+	return limiter
+}
+
+// limiterStore is a sharded, per-caller map of token-bucket Limiters for
+// a single route.
+type limiterStore struct {
+	spec   rateLimitSpec
+	shards [numLimiterShards]*limiterShard
+}
+
+func newLimiterStore(spec rateLimitSpec, maxItems int) *limiterStore {
+	perShard := maxItems / numLimiterShards
+	if perShard < 1 {
+		perShard = 1
+	}
+	s := &limiterStore{spec: spec}
+	for i := range s.shards {
+		s.shards[i] = &limiterShard{
+			maxItems: perShard,
+			order:    list.New(),
+			items:    make(map[string]*list.Element),
+		}
+	}
+	return s
+}
+
+func (s *limiterStore) get(key string) *rate.Limiter {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	shard := s.shards[h.Sum32()%numLimiterShards]
+	return shard.get(key, s.spec)
+}
+
+// rateLimiters rate-limits the routes configured via RATE_LIMIT_* env
+// vars, keyed by authenticated caller ARN (or source IP when
+// unauthenticated).
+type rateLimiters struct {
+	byRoute map[string]*limiterStore
+}
+
+func newRateLimiters() *rateLimiters {
+	rl := &rateLimiters{byRoute: make(map[string]*limiterStore)}
+	for routeKey, spec := range routeLimitsFromEnv() {
+		rl.byRoute[routeKey] = newLimiterStore(spec, maxLimitersPerRoute)
+	}
+	return rl
+}
+
+// middleware rate-limits matching routes. A caller over their limit gets
+// a 429 with a Retry-After header and a DataResponse-shaped JSON body, the
+// same shape the / and /data routes answer with.
+func (rl *rateLimiters) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		store, limited := rl.byRoute[r.Method+" "+r.URL.Path]
+		if !limited {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		limiter := store.get(rateLimitKey(r))
+		if !limiter.Allow() {
+			w.Header().Set("Retry-After", "1")
+			writeJSONResponse(w, DataResponse{
+				Message: "Rate limit exceeded",
+				Method:  r.Method,
+				Path:    r.URL.Path,
+			}, http.StatusTooManyRequests)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// rateLimitKey returns the authenticated caller's ARN, or the request's
+// source IP when unauthenticated.
+func rateLimitKey(r *http.Request) string {
+	if caller := callerFromContext(r.Context()); caller.UserARN != "" && caller.UserARN != "Unauthenticated Request" {
+		return caller.UserARN
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
@@ -0,0 +1,38 @@
+package server
+
+import "testing"
+
+func TestParseRateLimitSpec(t *testing.T) {
+	tests := []struct {
+		spec      string
+		wantLimit float64
+		wantBurst int
+		wantErr   bool
+	}{
+		{spec: "10/s", wantLimit: 10, wantBurst: 10},
+		{spec: "1/s", wantLimit: 1, wantBurst: 1},
+		{spec: "0/s", wantErr: true},
+		{spec: "-5/s", wantErr: true},
+		{spec: "10/m", wantErr: true},
+		{spec: "ten/s", wantErr: true},
+		{spec: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := parseRateLimitSpec(tt.spec)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseRateLimitSpec(%q) = %v, want error", tt.spec, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseRateLimitSpec(%q) returned unexpected error: %v", tt.spec, err)
+			}
+			if float64(got.limit) != tt.wantLimit || got.burst != tt.wantBurst {
+				t.Errorf("parseRateLimitSpec(%q) = %+v, want limit=%v burst=%v", tt.spec, got, tt.wantLimit, tt.wantBurst)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package server
+
+import "net/http"
+
+// Router adapts a Server's handler to a specific AWS Lambda Go HTTP
+// event version. apex/gateway v1 and v2 each implement this with the
+// same signature as their own package-level ListenAndServe, so mounting
+// either version behind a Server is a one-line choice made in a cmd/
+// binary rather than a divergence in handler code.
+//
+// That choice is made at compile time, by building cmd/apiv1 or
+// cmd/apiv2, rather than by a GATEWAY_VERSION=v1|v2 runtime switch: AWS
+// Lambda deploys one zip per function, so a given deployment only ever
+// needs one Router wired in, and a runtime switch would mean every
+// deployment pays the import weight of both apex/gateway majors for a
+// branch that's never taken. A new deployment target gets its own
+// cmd/apiVN directory importing the matching adapter package; Server and
+// its routes stay untouched.
+type Router interface {
+	ListenAndServe(addr string, h http.Handler) error
+}
@@ -0,0 +1,208 @@
+// Package server holds the HTTP handlers, caller verification and
+// response shapes shared by every deployment of this API. A cmd/
+// binary mounts a Server behind whichever apex/gateway major version its
+// Lambda function expects via the Router interface; the handlers
+// themselves don't know or care which one.
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/kaihendry/aws-lambda-rest-iam-api/iamauth"
+)
+
+type HealthResponse struct {
+	Status    string      `json:"status"`
+	Timestamp string      `json:"timestamp"`
+	Message   string      `json:"message"`
+	Caller    *CallerInfo `json:"caller,omitempty"`
+}
+
+type DataResponse struct {
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+	Method  string      `json:"method"`
+	Path    string      `json:"path"`
+	Caller  *CallerInfo `json:"caller,omitempty"`
+}
+
+// CallerInfo is the verified (or best-effort) identity of the caller of
+// a request, as produced by iamauth.
+type CallerInfo = iamauth.CallerInfo
+
+// Server holds the handlers and caller-verification state shared by
+// every route.
+type Server struct {
+	Mux          *http.ServeMux
+	Auth         *iamauth.Authenticator
+	rateLimiters *rateLimiters
+}
+
+// New builds a Server with its routes registered, an Authenticator
+// configured from the IAM_SERVER_ID / IAM_ALLOWED_* environment
+// variables, and rate limits configured from the RATE_LIMIT_* environment
+// variables.
+func New() *Server {
+	s := &Server{
+		Mux:          http.NewServeMux(),
+		Auth:         iamauth.NewAuthenticator(iamauth.ConfigFromEnv()),
+		rateLimiters: newRateLimiters(),
+	}
+	s.registerRoutes()
+	return s
+}
+
+func (s *Server) registerRoutes() {
+	s.Mux.HandleFunc("/", s.handleRoot)
+	s.Mux.HandleFunc("/health", s.handleHealth)
+	s.Mux.HandleFunc("/data", s.handleData)
+}
+
+// authenticateCaller verifies the caller via the iamauth pre-signed
+// GetCallerIdentity headers, if present, and falls back to an
+// unauthenticated CallerInfo otherwise.
+func (s *Server) authenticateCaller(r *http.Request) (*CallerInfo, error) {
+	if r.Header.Get(iamauth.HeaderMethod) == "" {
+		return &CallerInfo{UserARN: "Unauthenticated Request"}, nil
+	}
+	return s.Auth.Authenticate(r.Context(), r)
+}
+
+// ListenAndServe configures structured logging, wraps the Server's
+// routes with the access-log middleware, and serves them through r. When
+// running under Lambda, addr is ignored (as with apex/gateway); outside
+// Lambda it falls back to a plain http.ListenAndServe on PORT (default
+// 8080) so the binary works as a local dev server too.
+func (s *Server) ListenAndServe(r Router) error {
+	_, underLambda := os.LookupEnv("AWS_LAMBDA_FUNCTION_NAME")
+	ConfigureLogging(underLambda)
+
+	handler := s.accessLogMiddleware(s.rateLimiters.middleware(s.Mux))
+
+	if underLambda {
+		return r.ListenAndServe("", handler)
+	}
+
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8080"
+	}
+	slog.Info("Starting server", "port", port)
+	return http.ListenAndServe(fmt.Sprintf(":%s", port), handler)
+}
+
+// handleRoot is registered on "/", which http.ServeMux treats as a
+// catch-all for any path with no more specific registration, so it must
+// reject everything but the literal root itself to avoid answering 200
+// for typos and unknown paths.
+func (s *Server) handleRoot(w http.ResponseWriter, r *http.Request) {
+	caller := callerFromContext(r.Context())
+
+	if r.URL.Path != "/" {
+		response := DataResponse{
+			Message: "Not found",
+			Method:  r.Method,
+			Path:    r.URL.Path,
+			Caller:  caller,
+		}
+		writeJSONResponse(w, response, http.StatusNotFound)
+		return
+	}
+
+	response := DataResponse{
+		Message: "Welcome to AWS Lambda REST API with IAM Authentication",
+		Method:  r.Method,
+		Path:    r.URL.Path,
+		Caller:  caller,
+	}
+
+	writeJSONResponse(w, response, http.StatusOK)
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	caller := callerFromContext(r.Context())
+
+	response := HealthResponse{
+		Status:    "healthy",
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Message:   "API is running successfully",
+		Caller:    caller,
+	}
+
+	writeJSONResponse(w, response, http.StatusOK)
+}
+
+func (s *Server) handleData(w http.ResponseWriter, r *http.Request) {
+	caller := callerFromContext(r.Context())
+	if !s.Auth.Authorized(caller) {
+		slog.Warn("Caller not in allow-list", "caller", caller)
+		writeJSONResponse(w, DataResponse{
+			Message: "Forbidden: caller is not authorized for /data",
+			Method:  r.Method,
+			Path:    r.URL.Path,
+			Caller:  caller,
+		}, http.StatusForbidden)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		response := DataResponse{
+			Message: "Data retrieved successfully",
+			Data: map[string]interface{}{
+				"items": []string{"item1", "item2", "item3"},
+				"count": 3,
+			},
+			Method: r.Method,
+			Path:   r.URL.Path,
+			Caller: caller,
+		}
+		writeJSONResponse(w, response, http.StatusOK)
+
+	case http.MethodPost:
+		var requestData map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&requestData); err != nil {
+			response := DataResponse{
+				Message: "Invalid JSON in request body",
+				Method:  r.Method,
+				Path:    r.URL.Path,
+				Caller:  caller,
+			}
+			writeJSONResponse(w, response, http.StatusBadRequest)
+			return
+		}
+
+		response := DataResponse{
+			Message: "Data received successfully",
+			Data:    requestData,
+			Method:  r.Method,
+			Path:    r.URL.Path,
+			Caller:  caller,
+		}
+		writeJSONResponse(w, response, http.StatusCreated)
+
+	default:
+		response := DataResponse{
+			Message: fmt.Sprintf("Method %s not allowed", r.Method),
+			Method:  r.Method,
+			Path:    r.URL.Path,
+			Caller:  caller,
+		}
+		writeJSONResponse(w, response, http.StatusMethodNotAllowed)
+	}
+}
+
+func writeJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		slog.Error("Error encoding JSON response", "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kaihendry/aws-lambda-rest-iam-api/iamauth"
+)
+
+func TestHandlers(t *testing.T) {
+	s := New()
+	handler := s.accessLogMiddleware(s.rateLimiters.middleware(s.Mux))
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		expectedStatus int
+	}{
+		{
+			name:           "GET root endpoint",
+			method:         http.MethodGet,
+			path:           "/",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "GET health endpoint",
+			method:         http.MethodGet,
+			path:           "/health",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "GET data endpoint",
+			method:         http.MethodGet,
+			path:           "/data",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "POST data endpoint",
+			method:         http.MethodPost,
+			path:           "/data",
+			body:           `{"message":"test"}`,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "POST data endpoint with invalid JSON",
+			method:         http.MethodPost,
+			path:           "/data",
+			body:           `not json`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "404 for unknown endpoint",
+			method:         http.MethodGet,
+			path:           "/unknown",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewReader([]byte(tt.body)))
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+
+			if rec.Body.Len() == 0 {
+				return
+			}
+			var decoded map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &decoded); err != nil {
+				t.Errorf("response body is not valid JSON: %v", err)
+			}
+		})
+	}
+}
+
+func TestHandleDataForbiddenWhenNotAllowed(t *testing.T) {
+	s := New()
+	s.Auth = iamauth.NewAuthenticator(iamauth.Config{
+		AllowedAccountIDs: []string{"999999999999"},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/data", nil)
+	rec := httptest.NewRecorder()
+
+	s.Mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("expected status %d, got %d", http.StatusForbidden, rec.Code)
+	}
+}
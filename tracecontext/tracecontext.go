@@ -0,0 +1,87 @@
+// Package tracecontext parses the trace-propagation headers a request
+// arrives with and carries them on a context.Context, so a handler and
+// whatever AWS SDK calls it makes downstream can all log and forward the
+// same trace identifiers.
+package tracecontext
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Context holds the trace identifiers parsed from an inbound request,
+// whichever propagation format the caller used.
+type Context struct {
+	Root        string // X-Ray root trace ID, e.g. "1-5e1b4151-5ac6c58..."
+	Parent      string // X-Ray parent segment ID
+	Sampled     bool
+	TraceParent string // raw W3C traceparent header, forwarded as-is
+}
+
+type contextKey struct{}
+
+// ParseXRayTraceID parses the AWS X-Ray "X-Amzn-Trace-Id" header, of the
+// form "Root=1-...;Parent=...;Sampled=1".
+func ParseXRayTraceID(header string) (root, parent string, sampled bool) {
+	for _, field := range strings.Split(header, ";") {
+		kv := strings.SplitN(strings.TrimSpace(field), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "Root":
+			root = kv[1]
+		case "Parent":
+			parent = kv[1]
+		case "Sampled":
+			sampled = kv[1] == "1"
+		}
+	}
+	return root, parent, sampled
+}
+
+// FromRequest parses whatever trace propagation headers are present on
+// r. Root/Parent/Sampled come only from X-Ray's X-Amzn-Trace-Id; the raw
+// W3C traceparent header, if present, is kept alongside them unparsed so
+// it can be forwarded unchanged to downstream calls.
+func FromRequest(r *http.Request) Context {
+	tc := Context{TraceParent: r.Header.Get("traceparent")}
+	if header := r.Header.Get("X-Amzn-Trace-Id"); header != "" {
+		tc.Root, tc.Parent, tc.Sampled = ParseXRayTraceID(header)
+	}
+	return tc
+}
+
+// With returns a context carrying tc, so downstream AWS SDK calls made
+// for the lifetime of the request can correlate with it.
+func With(ctx context.Context, tc Context) context.Context {
+	return context.WithValue(ctx, contextKey{}, tc)
+}
+
+// FromContext returns the Context stashed by With, if any.
+func FromContext(ctx context.Context) (Context, bool) {
+	tc, ok := ctx.Value(contextKey{}).(Context)
+	return tc, ok
+}
+
+// XRayHeader rebuilds an "X-Amzn-Trace-Id" header value from tc's parsed
+// X-Ray fields, suitable for forwarding to a downstream AWS call under
+// the same trace. It returns "" if tc carries no X-Ray root.
+func (tc Context) XRayHeader() string {
+	if tc.Root == "" {
+		return ""
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Root=%s", tc.Root)
+	if tc.Parent != "" {
+		fmt.Fprintf(&b, ";Parent=%s", tc.Parent)
+	}
+	if tc.Sampled {
+		b.WriteString(";Sampled=1")
+	} else {
+		b.WriteString(";Sampled=0")
+	}
+	return b.String()
+}
@@ -0,0 +1,64 @@
+package tracecontext
+
+import "testing"
+
+func TestParseXRayTraceID(t *testing.T) {
+	tests := []struct {
+		header     string
+		wantRoot   string
+		wantParent string
+		wantSample bool
+	}{
+		{
+			header:     "Root=1-5e1b4151-5ac6c58dc39d1a28f6399a0c;Parent=53995c3f42cd8ad8;Sampled=1",
+			wantRoot:   "1-5e1b4151-5ac6c58dc39d1a28f6399a0c",
+			wantParent: "53995c3f42cd8ad8",
+			wantSample: true,
+		},
+		{
+			header:     "Root=1-5e1b4151-5ac6c58dc39d1a28f6399a0c;Sampled=0",
+			wantRoot:   "1-5e1b4151-5ac6c58dc39d1a28f6399a0c",
+			wantParent: "",
+			wantSample: false,
+		},
+		{
+			header:     "",
+			wantRoot:   "",
+			wantParent: "",
+			wantSample: false,
+		},
+		{
+			header:     "garbage",
+			wantRoot:   "",
+			wantParent: "",
+			wantSample: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			root, parent, sampled := ParseXRayTraceID(tt.header)
+			if root != tt.wantRoot || parent != tt.wantParent || sampled != tt.wantSample {
+				t.Errorf("ParseXRayTraceID(%q) = (%q, %q, %v), want (%q, %q, %v)",
+					tt.header, root, parent, sampled, tt.wantRoot, tt.wantParent, tt.wantSample)
+			}
+		})
+	}
+}
+
+func TestXRayHeaderRoundTrip(t *testing.T) {
+	tc := Context{Root: "1-5e1b4151-5ac6c58dc39d1a28f6399a0c", Parent: "53995c3f42cd8ad8", Sampled: true}
+	got := tc.XRayHeader()
+	root, parent, sampled := ParseXRayTraceID(got)
+	if root != tc.Root || parent != tc.Parent || sampled != tc.Sampled {
+		t.Errorf("XRayHeader() round-trip = %q, got (%q, %q, %v), want (%q, %q, %v)",
+			got, root, parent, sampled, tc.Root, tc.Parent, tc.Sampled)
+	}
+}
+
+func TestXRayHeaderEmptyRoot(t *testing.T) {
+	tc := Context{}
+	if got := tc.XRayHeader(); got != "" {
+		t.Errorf("XRayHeader() with no Root = %q, want empty string", got)
+	}
+}